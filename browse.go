@@ -0,0 +1,276 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/list"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/bakito/volumio-tui/internal/client"
+)
+
+// queueListItem adapts a client.QueueItem at a given queue position to
+// list.Item.
+type queueListItem struct {
+	item client.QueueItem
+	pos  int
+}
+
+func (i queueListItem) Title() string {
+	if i.item.Title != "" {
+		return i.item.Title
+	}
+	return i.item.URI
+}
+
+func (i queueListItem) Description() string {
+	switch {
+	case i.item.Artist != "" && i.item.Album != "":
+		return i.item.Artist + " — " + i.item.Album
+	case i.item.Artist != "":
+		return i.item.Artist
+	default:
+		return i.item.Service
+	}
+}
+
+func (i queueListItem) FilterValue() string { return i.item.Title }
+
+// browseListItem adapts a client.BrowseItem to list.Item.
+type browseListItem struct{ node client.BrowseItem }
+
+func (i browseListItem) Title() string {
+	if i.node.IsFolder() {
+		return "▸ " + i.node.Title
+	}
+	return i.node.Title
+}
+
+func (i browseListItem) Description() string { return i.node.Type }
+func (i browseListItem) FilterValue() string { return i.node.Title }
+
+// toQueueItem converts a browsable library entry to the shape AddToQueue and
+// ReplaceAndPlay expect.
+func toQueueItem(b client.BrowseItem) client.QueueItem {
+	return client.QueueItem{
+		URI:      b.URI,
+		Service:  b.Service,
+		Title:    b.Title,
+		AlbumArt: b.AlbumArt,
+	}
+}
+
+// newBrowsePaneList builds a bubbles/list.Model in the style the rest of the
+// TUI uses: no built-in help (the app renders its own), so it fits alongside
+// the now-playing view instead of taking over the whole screen.
+func newBrowsePaneList(title string) list.Model {
+	l := list.New(nil, list.NewDefaultDelegate(), 0, 0)
+	l.Title = title
+	l.SetShowHelp(false)
+	l.SetShowStatusBar(false)
+	return l
+}
+
+// setQueue replaces the queue list's contents with queue.
+func (m *model) setQueue(queue []client.QueueItem) {
+	items := make([]list.Item, len(queue))
+	for i, it := range queue {
+		items[i] = queueListItem{item: it, pos: i}
+	}
+	m.queueList.SetItems(items)
+}
+
+// refreshQueueCmd fetches the queue over REST, for the initial load and after
+// any queue-mutating action; live updates normally arrive via pushQueue
+// instead (see startSocketSubscription).
+func (m *model) refreshQueueCmd() tea.Cmd {
+	if m.client == nil {
+		return nil
+	}
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), httpTimeout)
+		defer cancel()
+		q, err := m.client.GetQueue(ctx)
+		if err != nil {
+			return errorMsg(err)
+		}
+		return queueMsg(q)
+	}
+}
+
+// browseCmd fetches the library listing at uri and updates the library list.
+// At the root it also refreshes the library-wide collection stats shown
+// below the list.
+func (m *model) browseCmd(uri string) tea.Cmd {
+	if m.client == nil {
+		return nil
+	}
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), httpTimeout)
+		defer cancel()
+		resp, err := m.client.Browse(ctx, uri)
+		if err != nil {
+			return errorMsg(err)
+		}
+		m.browseURI = uri
+		var nodes []client.BrowseItem
+		for _, lst := range resp.Navigation.Lists {
+			nodes = append(nodes, lst.Items...)
+		}
+		items := make([]list.Item, len(nodes))
+		for i, n := range nodes {
+			items[i] = browseListItem{node: n}
+		}
+		m.libraryList.SetItems(items)
+		m.libraryList.ResetSelected()
+		if uri == "" {
+			if stats, err := m.client.CollectionStats(ctx); err == nil {
+				m.libraryStats = stats
+			}
+		}
+		return nil
+	}
+}
+
+// browseSelect handles Enter in the browse pane: on the queue tab it plays
+// the selected position; on the library tab it navigates into folders and
+// replaces-and-plays tracks.
+func (m *model) browseSelect() tea.Cmd {
+	if m.client == nil {
+		return nil
+	}
+	if m.browseTab == browseTabQueue {
+		sel, ok := m.queueList.SelectedItem().(queueListItem)
+		if !ok {
+			return nil
+		}
+		m.loading = true
+		return m.simpleCmd(func(ctx context.Context) error { return m.client.PlayFromQueue(ctx, sel.pos) })
+	}
+
+	sel, ok := m.libraryList.SelectedItem().(browseListItem)
+	if !ok {
+		return nil
+	}
+	if sel.node.IsFolder() {
+		m.browseStack = append(m.browseStack, sel.node.URI)
+		return m.browseCmd(sel.node.URI)
+	}
+	m.loading = true
+	return m.simpleCmd(func(ctx context.Context) error { return m.client.ReplaceAndPlay(ctx, toQueueItem(sel.node)) })
+}
+
+// browseAddSelected adds the selected library item to the end of the queue.
+func (m *model) browseAddSelected() tea.Cmd {
+	if m.client == nil || m.browseTab != browseTabLibrary {
+		return nil
+	}
+	sel, ok := m.libraryList.SelectedItem().(browseListItem)
+	if !ok || sel.node.IsFolder() {
+		return nil
+	}
+	m.loading = true
+	return m.simpleQueueCmd(func(ctx context.Context) error { return m.client.AddToQueue(ctx, toQueueItem(sel.node)) })
+}
+
+// browseRemoveSelected removes the selected queue position from the queue.
+func (m *model) browseRemoveSelected() tea.Cmd {
+	if m.client == nil || m.browseTab != browseTabQueue {
+		return nil
+	}
+	sel, ok := m.queueList.SelectedItem().(queueListItem)
+	if !ok {
+		return nil
+	}
+	m.loading = true
+	return m.simpleQueueCmd(func(ctx context.Context) error { return m.client.RemoveFromQueue(ctx, sel.pos) })
+}
+
+// clearQueueCmd empties the play queue.
+func (m *model) clearQueueCmd() tea.Cmd {
+	if m.client == nil {
+		return nil
+	}
+	m.loading = true
+	return m.simpleQueueCmd(func(ctx context.Context) error { return m.client.ClearQueue(ctx) })
+}
+
+// simpleQueueCmd runs fn, then refreshes the queue list (rather than the
+// now-playing state refreshCmd refreshes) the same way simpleCmd does.
+func (m *model) simpleQueueCmd(fn func(context.Context) error) tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), httpTimeout)
+		defer cancel()
+		if err := fn(ctx); err != nil {
+			return errorMsg(err)
+		}
+		return m.refreshQueueCmd()()
+	}
+}
+
+// browseKeys bundles the extra key bindings active only in the browse pane.
+type browseKeys struct {
+	Browse  key.Binding
+	Tab     key.Binding
+	Back    key.Binding
+	Add     key.Binding
+	Replace key.Binding
+	Remove  key.Binding
+	Clear   key.Binding
+}
+
+func defaultBrowseKeys() browseKeys {
+	return browseKeys{
+		Browse:  key.NewBinding(key.WithKeys("b"), key.WithHelp("b", "browse")),
+		Tab:     key.NewBinding(key.WithKeys("tab"), key.WithHelp("tab", "switch queue/library")),
+		Back:    key.NewBinding(key.WithKeys("backspace"), key.WithHelp("backspace", "up a level")),
+		Add:     key.NewBinding(key.WithKeys("a"), key.WithHelp("a", "add to queue")),
+		Replace: key.NewBinding(key.WithKeys("p"), key.WithHelp("p", "play now")),
+		Remove:  key.NewBinding(key.WithKeys("x"), key.WithHelp("x", "remove")),
+		Clear:   key.NewBinding(key.WithKeys("c"), key.WithHelp("c", "clear queue")),
+	}
+}
+
+const (
+	browseTabQueue = iota
+	browseTabLibrary
+)
+
+// renderBrowse renders the queue/library pane, which View places below the
+// now-playing view. On terminals at least wideBrowseWidth columns wide the
+// queue and library lists are shown side by side; on narrower ones only the
+// current tab is shown, switched with Tab.
+const wideBrowseWidth = 100
+
+func (m *model) renderBrowse() string {
+	var b strings.Builder
+
+	if m.winW >= wideBrowseWidth {
+		b.WriteString("\n" + titleStyle.Render("Browse") + "\n")
+		panes := lipgloss.JoinHorizontal(lipgloss.Top, m.queueList.View(), "  ", m.libraryList.View())
+		b.WriteString(panes)
+	} else {
+		tab := "Queue"
+		if m.browseTab == browseTabLibrary {
+			tab = "Library"
+		}
+		b.WriteString("\n" + titleStyle.Render("Browse: "+tab) + "\n")
+		if m.browseTab == browseTabQueue {
+			b.WriteString(m.queueList.View())
+		} else {
+			b.WriteString(m.libraryList.View())
+		}
+	}
+	if m.browseTab == browseTabLibrary && m.browseURI == "" {
+		s := m.libraryStats
+		b.WriteString(dimStyle.Render(fmt.Sprintf(
+			"\n%d artists, %d albums, %d songs\n", s.Artists, s.Albums, s.Songs)))
+	}
+	b.WriteString(dimStyle.Render(
+		"\ntab switch, enter play/open, a add, p play now, x remove, c clear, backspace up, b/esc close\n"))
+	return b.String()
+}
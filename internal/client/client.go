@@ -2,26 +2,107 @@ package client
 
 import (
 	"context"
+	"crypto/tls"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"net"
 	"net/http"
 	"net/url"
 	"strings"
+	"sync"
 	"time"
+
+	"github.com/gorilla/websocket"
 )
 
 const (
 	HTTPTimeout = 5 * time.Second
+
+	defaultRetryAttempts = 1 // no retry by default, preserving prior behavior
+	defaultRetryBackoff  = 250 * time.Millisecond
 )
 
+// VolumioClient talks to a single Volumio instance's REST API.
 type VolumioClient struct {
 	baseURL string
 	http    *http.Client
+
+	username, password string
+	bearerToken        string
+	userAgent          string
+
+	retryAttempts int
+	retryBackoff  time.Duration
+
+	// mu guards socketConn, the live Socket.IO connection opened by
+	// Subscribe (see subscribe.go). It's nil until Subscribe dials.
+	mu         sync.Mutex
+	socketConn *websocket.Conn
+
+	// writeMu serializes writes to socketConn: gorilla/websocket requires
+	// callers not to invoke its write methods concurrently, but Publish is
+	// called from every command the TUI (and the MPRIS bridge) issue, each
+	// from its own goroutine.
+	writeMu sync.Mutex
+}
+
+// Option customizes a VolumioClient built by NewVolumioClient.
+type Option func(*VolumioClient)
+
+// WithHTTPClient replaces the client's underlying *http.Client entirely.
+func WithHTTPClient(h *http.Client) Option {
+	return func(c *VolumioClient) { c.http = h }
+}
+
+// WithTimeout overrides the default 5s request timeout.
+func WithTimeout(d time.Duration) Option {
+	return func(c *VolumioClient) { c.http.Timeout = d }
+}
+
+// WithBasicAuth sends HTTP Basic auth credentials on every request, for
+// Volumio instances placed behind a reverse proxy that requires it.
+func WithBasicAuth(username, password string) Option {
+	return func(c *VolumioClient) { c.username, c.password = username, password }
+}
+
+// WithBearerToken sends an Authorization: Bearer header on every request.
+func WithBearerToken(token string) Option {
+	return func(c *VolumioClient) { c.bearerToken = token }
+}
+
+// WithTLSConfig sets the TLS config used for https:// base URLs, e.g. to
+// trust a self-signed certificate.
+func WithTLSConfig(cfg *tls.Config) Option {
+	return func(c *VolumioClient) {
+		transport, ok := c.http.Transport.(*http.Transport)
+		if !ok || transport == nil {
+			transport = http.DefaultTransport.(*http.Transport).Clone()
+		} else {
+			transport = transport.Clone()
+		}
+		transport.TLSClientConfig = cfg
+		c.http.Transport = transport
+	}
+}
+
+// WithUserAgent overrides the default Go http.Client User-Agent.
+func WithUserAgent(ua string) Option {
+	return func(c *VolumioClient) { c.userAgent = ua }
 }
 
-func NewVolumioClient(base string) (*VolumioClient, error) {
+// WithRetry retries requests that fail with a network error or a 5xx status,
+// up to maxAttempts total tries, waiting backoff between attempts (doubling
+// each time). maxAttempts of 1 (the default) disables retrying.
+func WithRetry(maxAttempts int, backoff time.Duration) Option {
+	return func(c *VolumioClient) {
+		c.retryAttempts = maxAttempts
+		c.retryBackoff = backoff
+	}
+}
+
+func NewVolumioClient(base string, opts ...Option) (*VolumioClient, error) {
 	u, err := url.Parse(base)
 	if err != nil {
 		return nil, fmt.Errorf("invalid URL: %w", err)
@@ -32,36 +113,131 @@ func NewVolumioClient(base string) (*VolumioClient, error) {
 	if u.Host == "" {
 		return nil, errors.New("URL must include a host")
 	}
-	return &VolumioClient{
+
+	c := &VolumioClient{
 		baseURL: u.String(),
 		http: &http.Client{
 			Timeout: HTTPTimeout,
 		},
-	}, nil
+		retryAttempts: defaultRetryAttempts,
+		retryBackoff:  defaultRetryBackoff,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c, nil
 }
 
-func (c *VolumioClient) cmd(ctx context.Context, command string) error {
-	reqURL := fmt.Sprintf("%s/api/v1/commands/?cmd=%s", strings.TrimRight(c.baseURL, "/"), url.QueryEscape(command))
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, reqURL, http.NoBody)
-	if err != nil {
-		return err
+// request centralizes URL building, auth headers, retry-on-5xx/network-error
+// with exponential backoff, and response-status checking for every REST call
+// made by this package.
+func (c *VolumioClient) request(ctx context.Context, method, path string, query url.Values, body io.Reader) (*http.Response, error) {
+	reqURL := strings.TrimRight(c.baseURL, "/") + path
+	if len(query) > 0 {
+		reqURL += "?" + query.Encode()
+	}
+
+	var bodyBytes []byte
+	if body != nil {
+		b, err := io.ReadAll(body)
+		if err != nil {
+			return nil, err
+		}
+		bodyBytes = b
 	}
-	resp, err := c.http.Do(req)
+
+	backoff := c.retryBackoff
+	var lastErr error
+	for attempt := 1; attempt <= c.retryAttempts; attempt++ {
+		var reqBody io.Reader
+		if bodyBytes != nil {
+			reqBody = strings.NewReader(string(bodyBytes))
+		} else {
+			reqBody = http.NoBody
+		}
+
+		req, err := http.NewRequestWithContext(ctx, method, reqURL, reqBody)
+		if err != nil {
+			return nil, err
+		}
+		c.applyHeaders(req, bodyBytes != nil)
+
+		resp, err := c.http.Do(req)
+		switch {
+		case err != nil:
+			lastErr = err
+		case resp.StatusCode >= 500:
+			lastErr = fmt.Errorf("%s %s: status %d", method, path, resp.StatusCode)
+			resp.Body.Close()
+		case resp.StatusCode < 200 || resp.StatusCode >= 300:
+			resp.Body.Close()
+			return nil, fmt.Errorf("%s %s: status %d", method, path, resp.StatusCode)
+		default:
+			return resp, nil
+		}
+
+		if attempt == c.retryAttempts {
+			break
+		}
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+	}
+	return nil, lastErr
+}
+
+func (c *VolumioClient) applyHeaders(req *http.Request, hasJSONBody bool) {
+	if c.username != "" || c.password != "" {
+		req.SetBasicAuth(c.username, c.password)
+	}
+	if c.bearerToken != "" {
+		req.Header.Set("Authorization", "Bearer "+c.bearerToken)
+	}
+	if c.userAgent != "" {
+		req.Header.Set("User-Agent", c.userAgent)
+	}
+	if hasJSONBody {
+		req.Header.Set("Content-Type", "application/json")
+	}
+}
+
+// cmd issues a Volumio command endpoint call. command may be a bare command
+// name ("play") or a full query suffix ("volume&volume=50"); it's passed
+// through verbatim so callers building multi-parameter commands keep control
+// of their own escaping.
+func (c *VolumioClient) cmd(ctx context.Context, command string) error {
+	reqURL := fmt.Sprintf("/api/v1/commands/?cmd=%s", command)
+	resp, err := c.request(ctx, http.MethodGet, reqURL, nil, nil)
 	if err != nil {
-		return err
+		return fmt.Errorf("command %q failed: %w", command, err)
 	}
 	defer resp.Body.Close()
-	// Volumio may respond 200 or 204 for commands; treat 2xx as success.
-	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		return fmt.Errorf("command %q failed: status %d", command, resp.StatusCode)
-	}
 	return nil
 }
 
-func (c *VolumioClient) Play(ctx context.Context) error   { return c.cmd(ctx, "play") }
-func (c *VolumioClient) Pause(ctx context.Context) error  { return c.cmd(ctx, "pause") }
-func (c *VolumioClient) Stop(ctx context.Context) error   { return c.cmd(ctx, "stop") }
-func (c *VolumioClient) Toggle(ctx context.Context) error { return c.cmd(ctx, "toggle") }
+// command issues a playback command over the live Socket.IO connection
+// opened by Subscribe, if one is established, falling back to the REST
+// command endpoint otherwise.
+func (c *VolumioClient) command(ctx context.Context, cmdName string, args any) error {
+	if err := c.Publish(cmdName, args); err == nil {
+		return nil
+	}
+	return c.cmd(ctx, cmdName)
+}
+
+func (c *VolumioClient) Play(ctx context.Context) error   { return c.command(ctx, "play", nil) }
+func (c *VolumioClient) Pause(ctx context.Context) error  { return c.command(ctx, "pause", nil) }
+func (c *VolumioClient) Stop(ctx context.Context) error   { return c.command(ctx, "stop", nil) }
+func (c *VolumioClient) Toggle(ctx context.Context) error { return c.command(ctx, "toggle", nil) }
+
+// PlayURL instructs Volumio to stream rawURL directly, bypassing the queue.
+// Used e.g. to play a synthesized text-to-speech announcement.
+func (c *VolumioClient) PlayURL(ctx context.Context, rawURL string) error {
+	return c.cmd(ctx, fmt.Sprintf("playurl&url=%s", url.QueryEscape(rawURL)))
+}
 
 // SetVolume sets the absolute volume (0..100).
 func (c *VolumioClient) SetVolume(ctx context.Context, vol int) error {
@@ -71,50 +247,41 @@ func (c *VolumioClient) SetVolume(ctx context.Context, vol int) error {
 	if vol > 100 {
 		vol = 100
 	}
-	// Build the query properly so &volume is not escaped into the cmd value.
-	reqURL := fmt.Sprintf("%s/api/v1/commands/?cmd=volume&volume=%d", strings.TrimRight(c.baseURL, "/"), vol)
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, reqURL, http.NoBody)
-	if err != nil {
-		return err
-	}
-	resp, err := c.http.Do(req)
-	if err != nil {
-		return err
-	}
-	defer resp.Body.Close()
-	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		return fmt.Errorf("set volume failed: status %d", resp.StatusCode)
+	if err := c.Publish("volume", vol); err == nil {
+		return nil
 	}
-	return nil
+	return c.cmd(ctx, fmt.Sprintf("volume&volume=%d", vol))
 }
 
 func (c *VolumioClient) GetState(ctx context.Context) (State, error) {
 	var s State
-	reqURL := strings.TrimRight(c.baseURL, "/") + "/api/v1/getState"
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, reqURL, http.NoBody)
+	resp, err := c.request(ctx, http.MethodGet, "/api/v1/getState", nil, nil)
 	if err != nil {
-		return s, err
-	}
-	resp, err := c.http.Do(req)
-	if err != nil {
-		return s, err
+		return s, fmt.Errorf("getState failed: %w", err)
 	}
 	defer resp.Body.Close()
-	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		return s, fmt.Errorf("getState failed: status %d", resp.StatusCode)
-	}
-	dec := json.NewDecoder(resp.Body)
-	if err := dec.Decode(&s); err != nil {
+	if err := json.NewDecoder(resp.Body).Decode(&s); err != nil {
 		return s, err
 	}
 	return s, nil
 }
 
+// ProbeHost checks that c's host is reachable. It first looks for an mDNS
+// service record whose address equals c's hostname and, if one is found,
+// reports the host reachable without dialing it; the advertised port is not
+// compared. If discovery finds no match (no mDNS on this network, it's
+// disabled, or the host is configured by a name rather than its address) it
+// falls back to the old "try :80 then :3000" dial heuristic.
 func (c *VolumioClient) ProbeHost() error {
 	u, err := url.Parse(c.baseURL)
 	if err != nil {
 		return err
 	}
+
+	if probeViaDiscovery(u.Hostname()) {
+		return nil
+	}
+
 	host := u.Host
 	if !strings.Contains(host, ":") {
 		host += ":80"
@@ -137,3 +304,20 @@ func (c *VolumioClient) ProbeHost() error {
 	_ = conn.Close()
 	return nil
 }
+
+// probeViaDiscovery reports whether mDNS discovery turned up a Volumio host
+// matching hostname.
+func probeViaDiscovery(hostname string) bool {
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	hosts, err := Discover(ctx, 2*time.Second)
+	if err != nil {
+		return false
+	}
+	for _, h := range hosts {
+		if h.Address == hostname {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,143 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/grandcat/zeroconf"
+)
+
+// DiscoveredHost is a Volumio endpoint found via mDNS/Zeroconf.
+type DiscoveredHost struct {
+	Name    string
+	Address string
+	Port    int
+	Version string // empty if not advertised in the service TXT records
+}
+
+// BaseURL returns the HTTP base URL for h, suitable for NewVolumioClient.
+func (h DiscoveredHost) BaseURL() string {
+	return "http://" + net.JoinHostPort(h.Address, strconv.Itoa(h.Port))
+}
+
+// Discover browses the LAN for Volumio hosts via mDNS (_Volumio._tcp, falling
+// back to the generic _http._tcp) and returns whatever it finds. Each service
+// is browsed for timeout, so a call that falls back to _http._tcp can take up
+// to twice timeout unless ctx ends sooner. It's meant to save users from
+// hand-configuring a base URL.
+func Discover(ctx context.Context, timeout time.Duration) ([]DiscoveredHost, error) {
+	hosts, err := discoverService(ctx, "_Volumio._tcp", timeout)
+	if err != nil {
+		return nil, err
+	}
+	if len(hosts) > 0 {
+		return hosts, nil
+	}
+	return discoverService(ctx, "_http._tcp", timeout)
+}
+
+// DiscoverContinuous browses the LAN for Volumio hosts (_Volumio._tcp),
+// sending every entry it finds on found until ctx is cancelled. Unlike
+// Discover, it doesn't apply its own timeout or batch results, so it's meant
+// for long-lived device pickers that want to keep finding hosts for as long
+// as they're open; callers that only want the first host can cancel ctx
+// (e.g. via a timeout) as soon as they read one value.
+func DiscoverContinuous(ctx context.Context, found chan<- DiscoveredHost) error {
+	resolver, err := zeroconf.NewResolver(nil)
+	if err != nil {
+		return err
+	}
+
+	entries := make(chan *zeroconf.ServiceEntry)
+	go func() {
+		for e := range entries {
+			h, ok := toDiscoveredHost(e)
+			if !ok {
+				continue
+			}
+			select {
+			case found <- h:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	if err := resolver.Browse(ctx, "_Volumio._tcp", "local.", entries); err != nil {
+		return err
+	}
+	<-ctx.Done()
+	return ctx.Err()
+}
+
+func discoverService(ctx context.Context, service string, timeout time.Duration) ([]DiscoveredHost, error) {
+	resolver, err := zeroconf.NewResolver(nil)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	entries := make(chan *zeroconf.ServiceEntry)
+	var hosts []DiscoveredHost
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for e := range entries {
+			h, ok := toDiscoveredHost(e)
+			if ok {
+				hosts = append(hosts, h)
+			}
+		}
+	}()
+
+	if err := resolver.Browse(ctx, service, "local.", entries); err != nil {
+		return nil, fmt.Errorf("mDNS browse %s: %w", service, err)
+	}
+	// zeroconf closes entries itself once ctx is done; closing it here too
+	// would race with that and panic with "close of closed channel".
+	<-ctx.Done()
+	<-done
+	return hosts, nil
+}
+
+func toDiscoveredHost(e *zeroconf.ServiceEntry) (DiscoveredHost, bool) {
+	var addr string
+	switch {
+	case len(e.AddrIPv4) > 0:
+		addr = e.AddrIPv4[0].String()
+	case len(e.AddrIPv6) > 0:
+		addr = e.AddrIPv6[0].String()
+	case e.HostName != "":
+		addr = strings.TrimSuffix(e.HostName, ".")
+	}
+	if addr == "" || e.Port == 0 {
+		return DiscoveredHost{}, false
+	}
+	return DiscoveredHost{
+		Name:    e.Instance,
+		Address: addr,
+		Port:    e.Port,
+		Version: txtValue(e.Text, "version"),
+	}, true
+}
+
+func txtValue(txt []string, key string) string {
+	prefix := key + "="
+	for _, rec := range txt {
+		if v, ok := strings.CutPrefix(rec, prefix); ok {
+			return v
+		}
+	}
+	return ""
+}
+
+// NewVolumioClientForHost builds a VolumioClient for a host found via Discover.
+func NewVolumioClientForHost(h DiscoveredHost) (*VolumioClient, error) {
+	return NewVolumioClient(h.BaseURL())
+}
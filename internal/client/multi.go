@@ -0,0 +1,189 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+// Zone is a single Volumio endpoint managed by a MultiClient.
+type Zone struct {
+	Name   string
+	Client *VolumioClient
+}
+
+// MultiClient fans out transport and volume commands to a set of Volumio
+// endpoints ("zones") and aggregates their state. Volumio installations
+// commonly run one instance per room; MultiClient lets a caller address them
+// as a single player, a synchronized group, or individually.
+type MultiClient struct {
+	mu    sync.RWMutex
+	zones map[string]*Zone
+	group map[string]bool // active group; empty means "all zones"
+}
+
+// NewMultiClient returns an empty MultiClient with no zones.
+func NewMultiClient() *MultiClient {
+	return &MultiClient{zones: make(map[string]*Zone)}
+}
+
+// AddZone registers a new zone under name, backed by a client for baseURL.
+// It doesn't contact the host.
+func (m *MultiClient) AddZone(name, baseURL string) error {
+	c, err := NewVolumioClient(baseURL)
+	if err != nil {
+		return fmt.Errorf("add zone %q: %w", name, err)
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.zones[name] = &Zone{Name: name, Client: c}
+	return nil
+}
+
+// RemoveZone drops a zone and removes it from the active group, if present.
+func (m *MultiClient) RemoveZone(name string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.zones, name)
+	delete(m.group, name)
+}
+
+// Zones returns the currently registered zones.
+func (m *MultiClient) Zones() []*Zone {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	zones := make([]*Zone, 0, len(m.zones))
+	for _, z := range m.zones {
+		zones = append(zones, z)
+	}
+	return zones
+}
+
+// Group restricts subsequent transport/volume commands to the named zones.
+// Calling Group with no arguments clears the restriction, targeting all zones.
+func (m *MultiClient) Group(names ...string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if len(names) == 0 {
+		m.group = nil
+		return nil
+	}
+	group := make(map[string]bool, len(names))
+	for _, n := range names {
+		if _, ok := m.zones[n]; !ok {
+			return fmt.Errorf("group: unknown zone %q", n)
+		}
+		group[n] = true
+	}
+	m.group = group
+	return nil
+}
+
+// targets returns the zones a broadcast command should apply to: the active
+// group if one is set, otherwise every registered zone.
+func (m *MultiClient) targets() []*Zone {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	targets := make([]*Zone, 0, len(m.zones))
+	for name, z := range m.zones {
+		if len(m.group) == 0 || m.group[name] {
+			targets = append(targets, z)
+		}
+	}
+	return targets
+}
+
+// broadcast runs fn against every targeted zone concurrently, collecting any
+// per-zone errors into a single combined error.
+func (m *MultiClient) broadcast(fn func(*VolumioClient) error) error {
+	targets := m.targets()
+	errs := make([]error, len(targets))
+	var wg sync.WaitGroup
+	for i, z := range targets {
+		wg.Add(1)
+		go func(i int, z *Zone) {
+			defer wg.Done()
+			if err := fn(z.Client); err != nil {
+				errs[i] = fmt.Errorf("zone %q: %w", z.Name, err)
+			}
+		}(i, z)
+	}
+	wg.Wait()
+
+	var combined error
+	for _, err := range errs {
+		if err == nil {
+			continue
+		}
+		if combined == nil {
+			combined = err
+		} else {
+			combined = fmt.Errorf("%w; %w", combined, err)
+		}
+	}
+	return combined
+}
+
+func (m *MultiClient) Play(ctx context.Context) error {
+	return m.broadcast(func(c *VolumioClient) error { return c.Play(ctx) })
+}
+
+func (m *MultiClient) Pause(ctx context.Context) error {
+	return m.broadcast(func(c *VolumioClient) error { return c.Pause(ctx) })
+}
+
+func (m *MultiClient) Stop(ctx context.Context) error {
+	return m.broadcast(func(c *VolumioClient) error { return c.Stop(ctx) })
+}
+
+// BroadcastVolume adjusts every targeted zone's volume by delta, relative to
+// each zone's own current volume.
+func (m *MultiClient) BroadcastVolume(ctx context.Context, delta int) error {
+	return m.broadcast(func(c *VolumioClient) error {
+		s, err := c.GetState(ctx)
+		if err != nil {
+			return err
+		}
+		return c.SetVolume(ctx, s.Volume+delta)
+	})
+}
+
+// Subscribe merges per-zone state subscriptions into a single channel that
+// emits the full {zone name: State} snapshot whenever any zone changes.
+func (m *MultiClient) Subscribe(ctx context.Context) (<-chan map[string]State, error) {
+	out := make(chan map[string]State)
+	zones := m.Zones()
+
+	var mu sync.Mutex
+	snapshot := make(map[string]State, len(zones))
+
+	for _, z := range zones {
+		stateCh, queueCh, err := z.Client.Subscribe(ctx)
+		if err != nil {
+			continue
+		}
+		// MultiClient only aggregates state; drain queueCh so per-zone
+		// pushQueue events don't block on an unread channel.
+		go func(ch <-chan []QueueItem) {
+			for range ch {
+			}
+		}(queueCh)
+		go func(name string, ch <-chan State) {
+			for s := range ch {
+				mu.Lock()
+				snapshot[name] = s
+				cp := make(map[string]State, len(snapshot))
+				for k, v := range snapshot {
+					cp[k] = v
+				}
+				mu.Unlock()
+				select {
+				case out <- cp:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}(z.Name, stateCh)
+	}
+	return out, nil
+}
@@ -0,0 +1,112 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+func (c *VolumioClient) Prev(ctx context.Context) error { return c.cmd(ctx, "prev") }
+func (c *VolumioClient) Next(ctx context.Context) error { return c.cmd(ctx, "next") }
+
+func (c *VolumioClient) Repeat(ctx context.Context, on bool) error {
+	return c.cmd(ctx, fmt.Sprintf("repeat&value=%t", on))
+}
+func (c *VolumioClient) Random(ctx context.Context, on bool) error {
+	return c.cmd(ctx, fmt.Sprintf("random&value=%t", on))
+}
+func (c *VolumioClient) Consume(ctx context.Context, on bool) error {
+	return c.cmd(ctx, fmt.Sprintf("consume&value=%t", on))
+}
+
+// Seek jumps playback to the given position, in seconds.
+func (c *VolumioClient) Seek(ctx context.Context, seconds int) error {
+	if err := c.Publish("seek", seconds); err == nil {
+		return nil
+	}
+	return c.cmd(ctx, fmt.Sprintf("seek&position=%d", seconds))
+}
+
+// GetQueue returns the current play queue.
+func (c *VolumioClient) GetQueue(ctx context.Context) ([]QueueItem, error) {
+	var resp struct {
+		Queue []QueueItem `json:"queue"`
+	}
+	if err := c.getJSON(ctx, "/api/v1/getQueue", nil, &resp); err != nil {
+		return nil, err
+	}
+	return resp.Queue, nil
+}
+
+// Browse lists the contents of a browsable URI. An empty uri returns the
+// root of Volumio's browse sources.
+func (c *VolumioClient) Browse(ctx context.Context, uri string) (BrowseResponse, error) {
+	var resp BrowseResponse
+	query := url.Values{"uri": {uri}}
+	err := c.getJSON(ctx, "/api/v1/browse", query, &resp)
+	return resp, err
+}
+
+// PlayFromQueue plays the queue item at the given zero-based position.
+func (c *VolumioClient) PlayFromQueue(ctx context.Context, pos int) error {
+	return c.cmd(ctx, fmt.Sprintf("play&N=%d", pos))
+}
+
+// RemoveFromQueue removes the queue item at the given zero-based position.
+func (c *VolumioClient) RemoveFromQueue(ctx context.Context, pos int) error {
+	return c.postJSON(ctx, "/api/v1/removeFromQueue", map[string]int{"value": pos}, nil)
+}
+
+// ClearQueue empties the play queue.
+func (c *VolumioClient) ClearQueue(ctx context.Context) error {
+	return c.cmd(ctx, "clearQueue")
+}
+
+// AddToQueue appends item to the end of the play queue.
+func (c *VolumioClient) AddToQueue(ctx context.Context, item QueueItem) error {
+	return c.postJSON(ctx, "/api/v1/addToQueue", item, nil)
+}
+
+// ReplaceAndPlay clears the queue, queues item, and starts playing it.
+func (c *VolumioClient) ReplaceAndPlay(ctx context.Context, item QueueItem) error {
+	return c.postJSON(ctx, "/api/v1/replaceAndPlay", item, nil)
+}
+
+// CollectionStats returns library-wide counts (artists/albums/songs).
+func (c *VolumioClient) CollectionStats(ctx context.Context) (CollectionStats, error) {
+	var stats CollectionStats
+	err := c.getJSON(ctx, "/api/v1/collectionstats", nil, &stats)
+	return stats, err
+}
+
+// getJSON issues a GET request against path with query and decodes the JSON
+// response body into out, going through the shared retry/auth request path.
+func (c *VolumioClient) getJSON(ctx context.Context, path string, query url.Values, out any) error {
+	resp, err := c.request(ctx, http.MethodGet, path, query, nil)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+// postJSON POSTs body as JSON to path and, if out is non-nil, decodes the
+// response body into it, going through the shared retry/auth request path.
+func (c *VolumioClient) postJSON(ctx context.Context, path string, body, out any) error {
+	buf, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+	resp, err := c.request(ctx, http.MethodPost, path, nil, bytes.NewReader(buf))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if out == nil {
+		return nil
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
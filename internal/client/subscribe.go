@@ -0,0 +1,212 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// subscribeBackoffMin/Max mirror the jpillora/backoff defaults: start small
+// and double up to a ceiling so a flaky Volumio host doesn't get hammered by
+// reconnect attempts.
+const (
+	subscribeBackoffMin = 500 * time.Millisecond
+	subscribeBackoffMax = 30 * time.Second
+
+	// pollFallbackInterval is used only when the socket handshake never
+	// succeeds, so existing poll-based consumers keep working.
+	pollFallbackInterval = 2 * time.Second
+)
+
+// Subscribe opens Volumio's Socket.IO endpoint and streams pushState and
+// pushQueue events on the returned channels until ctx is cancelled.
+// Disconnects are retried with exponential backoff; if the very first
+// handshake fails, Subscribe falls back to polling GetState on
+// pollFallbackInterval (the queue channel is closed immediately in that
+// case) so callers that can't reach the socket endpoint (e.g. it's disabled,
+// or blocked by a reverse proxy) still receive state updates.
+func (c *VolumioClient) Subscribe(ctx context.Context) (<-chan State, <-chan []QueueItem, error) {
+	stateOut := make(chan State)
+	queueOut := make(chan []QueueItem)
+
+	conn, err := c.dialSocket(ctx)
+	if err != nil {
+		close(queueOut)
+		go c.pollFallback(ctx, stateOut)
+		return stateOut, queueOut, nil
+	}
+
+	go c.runSubscription(ctx, conn, stateOut, queueOut)
+	return stateOut, queueOut, nil
+}
+
+// Publish sends cmdName over the live Socket.IO connection opened by
+// Subscribe, with args as its payload, returning an error (so callers fall
+// back to REST) if no socket connection is currently established.
+func (c *VolumioClient) Publish(cmdName string, args any) error {
+	c.mu.Lock()
+	conn := c.socketConn
+	c.mu.Unlock()
+	if conn == nil {
+		return errors.New("no live socket connection")
+	}
+	payload, err := json.Marshal(args)
+	if err != nil {
+		return err
+	}
+	frame := fmt.Sprintf(`42["%s",%s]`, cmdName, payload)
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+	return conn.WriteMessage(websocket.TextMessage, []byte(frame))
+}
+
+// dialSocket performs the Engine.IO/Socket.IO handshake over WebSocket and
+// subscribes to state and queue pushes.
+func (c *VolumioClient) dialSocket(ctx context.Context) (*websocket.Conn, error) {
+	wsURL := strings.Replace(strings.TrimRight(c.baseURL, "/"), "http", "ws", 1) +
+		"/socket.io/?EIO=3&transport=websocket"
+
+	dialer := websocket.Dialer{HandshakeTimeout: HTTPTimeout}
+	conn, _, err := dialer.DialContext(ctx, wsURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("socket.io dial: %w", err)
+	}
+	// Ask Volumio for initial snapshots; pushState/pushQueue events follow on
+	// their own as the state changes.
+	c.writeMu.Lock()
+	err = conn.WriteMessage(websocket.TextMessage, []byte(`42["getState"]`))
+	if err == nil {
+		err = conn.WriteMessage(websocket.TextMessage, []byte(`42["getQueue"]`))
+	}
+	c.writeMu.Unlock()
+	if err != nil {
+		_ = conn.Close()
+		return nil, fmt.Errorf("socket.io handshake: %w", err)
+	}
+
+	c.mu.Lock()
+	c.socketConn = conn
+	c.mu.Unlock()
+	return conn, nil
+}
+
+// runSubscription reads pushState/pushQueue frames off conn and forwards them
+// to stateOut/queueOut, reconnecting with exponential backoff whenever the
+// connection drops.
+func (c *VolumioClient) runSubscription(ctx context.Context, conn *websocket.Conn, stateOut chan<- State, queueOut chan<- []QueueItem) {
+	defer close(stateOut)
+	defer close(queueOut)
+	defer func() {
+		c.mu.Lock()
+		c.socketConn = nil
+		c.mu.Unlock()
+	}()
+
+	// Close the current connection once ctx is done, so readPushEvents
+	// returns instead of blocking until the connection drops on its own.
+	go func() {
+		<-ctx.Done()
+		c.mu.Lock()
+		if c.socketConn != nil {
+			_ = c.socketConn.Close()
+		}
+		c.mu.Unlock()
+	}()
+
+	backoff := subscribeBackoffMin
+	for {
+		if err := c.readPushEvents(conn, stateOut, queueOut); err != nil {
+			_ = conn.Close()
+		}
+
+		if ctx.Err() != nil {
+			return
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(backoff):
+		}
+
+		backoff *= 2
+		if backoff > subscribeBackoffMax {
+			backoff = subscribeBackoffMax
+		}
+
+		next, err := c.dialSocket(ctx)
+		if err != nil {
+			continue
+		}
+		conn = next
+		backoff = subscribeBackoffMin
+	}
+}
+
+// readPushEvents blocks reading frames off conn, decoding "pushState" and
+// "pushQueue" Socket.IO events and sending them on stateOut/queueOut, until
+// conn errors.
+func (c *VolumioClient) readPushEvents(conn *websocket.Conn, stateOut chan<- State, queueOut chan<- []QueueItem) error {
+	for {
+		_, data, err := conn.ReadMessage()
+		if err != nil {
+			return err
+		}
+
+		// Socket.IO v2 event frames look like: 42["pushState",{...}]
+		payload := string(data)
+		if !strings.HasPrefix(payload, "42[") {
+			continue
+		}
+		var frame []json.RawMessage
+		if err := json.Unmarshal([]byte(payload[2:]), &frame); err != nil || len(frame) < 2 {
+			continue
+		}
+		var event string
+		if err := json.Unmarshal(frame[0], &event); err != nil {
+			continue
+		}
+		switch event {
+		case "pushState":
+			var s State
+			if err := json.Unmarshal(frame[1], &s); err == nil {
+				stateOut <- s
+			}
+		case "pushQueue":
+			var q []QueueItem
+			if err := json.Unmarshal(frame[1], &q); err == nil {
+				queueOut <- q
+			}
+		}
+	}
+}
+
+// pollFallback emulates Subscribe's state channel by polling GetState on an
+// interval, used when the socket.io handshake never succeeds.
+func (c *VolumioClient) pollFallback(ctx context.Context, out chan<- State) {
+	defer close(out)
+	ticker := time.NewTicker(pollFallbackInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s, err := c.GetState(ctx)
+			if err != nil {
+				continue
+			}
+			select {
+			case out <- s:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}
+}
@@ -19,4 +19,58 @@ type State struct {
 	Channels     int     `json:"channels"`
 	Updated      string  `json:"updated"`
 	DisableState bool    `json:"disableUiControls"`
+	AlbumArt     string  `json:"albumart"`
+}
+
+// QueueItem is a single track as Volumio represents it in the play queue.
+type QueueItem struct {
+	URI      string `json:"uri"`
+	Service  string `json:"service"`
+	Title    string `json:"title"`
+	Artist   string `json:"artist,omitempty"`
+	Album    string `json:"album,omitempty"`
+	AlbumArt string `json:"albumart,omitempty"`
+	Duration int    `json:"duration,omitempty"`
+}
+
+// BrowseResponse is Volumio's response to /api/v1/browse?uri=...
+type BrowseResponse struct {
+	Navigation BrowseNavigation `json:"navigation"`
+}
+
+type BrowseNavigation struct {
+	Lists []BrowseList `json:"lists"`
+}
+
+// BrowseList is one column of a browse response, e.g. artists or tracks.
+type BrowseList struct {
+	Items []BrowseItem `json:"items"`
+}
+
+// BrowseItem is a single browsable entry: a folder, a playlist, a track, ...
+type BrowseItem struct {
+	Service  string `json:"service"`
+	Type     string `json:"type"`
+	Title    string `json:"title"`
+	URI      string `json:"uri"`
+	Icon     string `json:"icon,omitempty"`
+	AlbumArt string `json:"albumart,omitempty"`
+}
+
+// IsFolder reports whether i should be navigated into via Browse, rather
+// than played directly.
+func (i BrowseItem) IsFolder() bool {
+	switch i.Type {
+	case "folder", "playlist", "streaming-category", "radio-category":
+		return true
+	default:
+		return false
+	}
+}
+
+// CollectionStats is Volumio's response to /api/v1/collectionstats.
+type CollectionStats struct {
+	Artists int `json:"artists"`
+	Albums  int `json:"albums"`
+	Songs   int `json:"songs"`
 }
@@ -0,0 +1,231 @@
+// Package mpris bridges a client.VolumioClient onto the Linux desktop by
+// registering an MPRIS2 (https://specifications.freedesktop.org/mpris-spec)
+// D-Bus service, so media keys, GNOME/KDE panels and playerctl can control
+// Volumio like any other local player.
+package mpris
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/godbus/dbus/v5"
+	"github.com/godbus/dbus/v5/introspect"
+	"github.com/godbus/dbus/v5/prop"
+
+	"github.com/bakito/volumio-tui/internal/client"
+)
+
+const (
+	busName  = "org.mpris.MediaPlayer2.volumio"
+	objPath  = dbus.ObjectPath("/org/mpris/MediaPlayer2")
+	ifaceApp = "org.mpris.MediaPlayer2"
+	ifacePl  = "org.mpris.MediaPlayer2.Player"
+)
+
+// Player exposes c as an MPRIS2 media player on the session D-Bus.
+type Player struct {
+	client *client.VolumioClient
+	conn   *dbus.Conn
+	props  *prop.Properties
+}
+
+// New connects to the session bus, claims org.mpris.MediaPlayer2.volumio and
+// exports the MediaPlayer2/MediaPlayer2.Player interfaces backed by c. Call
+// Run with a state subscription channel to keep properties live, and Close
+// to release the bus name.
+func New(c *client.VolumioClient) (*Player, error) {
+	conn, err := dbus.ConnectSessionBus()
+	if err != nil {
+		return nil, fmt.Errorf("mpris: connect session bus: %w", err)
+	}
+
+	p := &Player{client: c, conn: conn}
+	if err := p.export(); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	return p, nil
+}
+
+func (p *Player) export() error {
+	if err := p.conn.Export(p, objPath, ifaceApp); err != nil {
+		return fmt.Errorf("mpris: export %s: %w", ifaceApp, err)
+	}
+	// SeekBy is remapped to the D-Bus method name "Seek": naming the Go
+	// method itself Seek trips go vet's stdmethods check, which flags any
+	// method called Seek against io.Seeker's signature regardless of its
+	// actual (unrelated) one.
+	seekMap := map[string]string{"SeekBy": "Seek"}
+	if err := p.conn.ExportWithMap(p, seekMap, objPath, ifacePl); err != nil {
+		return fmt.Errorf("mpris: export %s: %w", ifacePl, err)
+	}
+
+	props, err := prop.Export(p.conn, objPath, map[string]map[string]*prop.Prop{
+		ifaceApp: {
+			"CanQuit":             {Value: false},
+			"CanRaise":            {Value: false},
+			"HasTrackList":        {Value: false},
+			"Identity":            {Value: "Volumio"},
+			"DesktopEntry":        {Value: "volumio-tui"},
+			"SupportedUriSchemes": {Value: []string{"http", "https"}},
+			"SupportedMimeTypes":  {Value: []string{}},
+		},
+		ifacePl: {
+			"PlaybackStatus": {Value: "Stopped", Emit: prop.EmitTrue},
+			"LoopStatus":     {Value: "None", Emit: prop.EmitTrue},
+			"Rate":           {Value: 1.0, Emit: prop.EmitTrue},
+			"Shuffle":        {Value: false, Emit: prop.EmitTrue},
+			"Metadata":       {Value: map[string]dbus.Variant{}, Emit: prop.EmitTrue},
+			"Volume":         {Value: 0.0, Writable: true, Emit: prop.EmitTrue, Callback: p.onVolumeSet},
+			"Position":       {Value: int64(0), Emit: prop.EmitFalse},
+			"MinimumRate":    {Value: 1.0},
+			"MaximumRate":    {Value: 1.0},
+			"CanGoNext":      {Value: true},
+			"CanGoPrevious":  {Value: true},
+			"CanPlay":        {Value: true},
+			"CanPause":       {Value: true},
+			"CanSeek":        {Value: true},
+			"CanControl":     {Value: true},
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("mpris: export properties: %w", err)
+	}
+	p.props = props
+
+	node := &introspect.Node{
+		Name: string(objPath),
+		Interfaces: []introspect.Interface{
+			introspect.IntrospectData,
+			prop.IntrospectData,
+		},
+	}
+	if err := p.conn.Export(introspect.NewIntrospectable(node), objPath, "org.freedesktop.DBus.Introspectable"); err != nil {
+		return fmt.Errorf("mpris: export introspectable: %w", err)
+	}
+
+	reply, err := p.conn.RequestName(busName, dbus.NameFlagDoNotQueue)
+	if err != nil {
+		return fmt.Errorf("mpris: request name %s: %w", busName, err)
+	}
+	if reply != dbus.RequestNameReplyPrimaryOwner {
+		return fmt.Errorf("mpris: name %s already taken", busName)
+	}
+	return nil
+}
+
+// MediaPlayer2 methods. Raise and Quit are no-ops: this is a headless bridge,
+// not a window to raise or a process for an MPRIS client to terminate.
+
+func (p *Player) Raise() *dbus.Error { return nil }
+func (p *Player) Quit() *dbus.Error  { return nil }
+
+// MediaPlayer2.Player methods.
+
+func (p *Player) Play() *dbus.Error      { return p.call(p.client.Play) }
+func (p *Player) Pause() *dbus.Error     { return p.call(p.client.Pause) }
+func (p *Player) PlayPause() *dbus.Error { return p.call(p.client.Toggle) }
+func (p *Player) Stop() *dbus.Error      { return p.call(p.client.Stop) }
+func (p *Player) Next() *dbus.Error      { return p.call(p.client.Next) }
+func (p *Player) Previous() *dbus.Error  { return p.call(p.client.Prev) }
+
+// SeekBy jumps playback forward (or backward) by offsetUs microseconds,
+// relative to the last known position. Exported on D-Bus as "Seek" (see
+// export, which remaps the name).
+func (p *Player) SeekBy(offsetUs int64) *dbus.Error {
+	lastPos, _ := p.props.Get(ifacePl, "Position")
+	current, _ := lastPos.Value().(int64)
+	target := (current + offsetUs) / 1_000_000
+	if target < 0 {
+		target = 0
+	}
+	return p.call(func(ctx context.Context) error { return p.client.Seek(ctx, int(target)) })
+}
+
+// SetPosition seeks to an absolute position, in microseconds. trackID is
+// accepted but ignored: Volumio has no notion of a track identifier to
+// validate it against, same as most single-queue players implementing MPRIS.
+func (p *Player) SetPosition(_ dbus.ObjectPath, positionUs int64) *dbus.Error {
+	return p.call(func(ctx context.Context) error { return p.client.Seek(ctx, int(positionUs/1_000_000)) })
+}
+
+func (p *Player) call(fn func(context.Context) error) *dbus.Error {
+	if err := fn(context.Background()); err != nil {
+		return dbus.MakeFailedError(err)
+	}
+	return nil
+}
+
+// onVolumeSet is invoked by godbus/prop when an MPRIS client writes Volume
+// (0.0..1.0); it forwards the change to Volumio.
+func (p *Player) onVolumeSet(c *prop.Change) *dbus.Error {
+	vol, ok := c.Value.(float64)
+	if !ok {
+		return dbus.MakeFailedError(fmt.Errorf("mpris: bad Volume value %v", c.Value))
+	}
+	if err := p.client.SetVolume(context.Background(), int(vol*100)); err != nil {
+		return dbus.MakeFailedError(err)
+	}
+	return nil
+}
+
+// Run publishes state updates from ch as MPRIS property changes until ch is
+// closed or ctx is cancelled.
+func (p *Player) Run(ctx context.Context, ch <-chan client.State) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case s, ok := <-ch:
+			if !ok {
+				return
+			}
+			p.publish(s)
+		}
+	}
+}
+
+func (p *Player) publish(s client.State) {
+	p.props.SetMust(ifacePl, "PlaybackStatus", playbackStatus(s))
+	p.props.SetMust(ifacePl, "Metadata", metadata(s))
+	p.props.SetMust(ifacePl, "Volume", float64(s.Volume)/100)
+	p.props.SetMust(ifacePl, "Position", int64(s.Seek)*1000)
+}
+
+// Close releases the D-Bus connection and the claimed bus name.
+func (p *Player) Close() error {
+	_, _ = p.conn.ReleaseName(busName)
+	return p.conn.Close()
+}
+
+func playbackStatus(s client.State) string {
+	switch s.Status {
+	case "play":
+		return "Playing"
+	case "pause":
+		return "Paused"
+	default:
+		return "Stopped"
+	}
+}
+
+// trackID builds a stable-ish MPRIS track object path from a string
+// identifying the track; metadata passes the service, title and artist
+// concatenated, since State carries no track URI.
+func trackID(uri string) dbus.ObjectPath {
+	if uri == "" {
+		return dbus.ObjectPath("/org/mpris/MediaPlayer2/volumio/NoTrack")
+	}
+	return dbus.ObjectPath(fmt.Sprintf("/org/mpris/MediaPlayer2/volumio/track/%x", []byte(uri)))
+}
+
+func metadata(s client.State) map[string]dbus.Variant {
+	return map[string]dbus.Variant{
+		"mpris:trackid": dbus.MakeVariant(trackID(s.Service + s.Title + s.Artist)),
+		"mpris:length":  dbus.MakeVariant(int64(s.Duration * 1e6)),
+		"mpris:artUrl":  dbus.MakeVariant(s.AlbumArt),
+		"xesam:title":   dbus.MakeVariant(s.Title),
+		"xesam:artist":  dbus.MakeVariant([]string{s.Artist}),
+		"xesam:album":   dbus.MakeVariant(s.Album),
+	}
+}
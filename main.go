@@ -4,13 +4,9 @@ import (
 	"context"
 	_ "embed"
 	"encoding/base64"
-	"encoding/json"
 	"errors"
 	"flag"
 	"fmt"
-	"net"
-	"net/http"
-	"net/url"
 	"os"
 	"strconv"
 	"strings"
@@ -18,10 +14,13 @@ import (
 
 	"github.com/charmbracelet/bubbles/help"
 	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/list"
 	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
-	"github.com/grandcat/zeroconf"
+
+	"github.com/bakito/volumio-tui/internal/client"
+	"github.com/bakito/volumio-tui/internal/mpris"
 )
 
 // Embed the logo so path issues can't break rendering.
@@ -36,121 +35,6 @@ const (
 
 var Version = "devel"
 
-type volumioClient struct {
-	baseURL string
-	http    *http.Client
-}
-
-func newVolumioClient(base string) (*volumioClient, error) {
-	u, err := url.Parse(base)
-	if err != nil {
-		return nil, fmt.Errorf("invalid URL: %w", err)
-	}
-	if u.Scheme == "" {
-		u.Scheme = "http"
-	}
-	if u.Host == "" {
-		return nil, errors.New("URL must include a host")
-	}
-	return &volumioClient{
-		baseURL: u.String(),
-		http: &http.Client{
-			Timeout: httpTimeout,
-		},
-	}, nil
-}
-
-func (c *volumioClient) cmd(ctx context.Context, command string) error {
-	reqURL := fmt.Sprintf("%s/api/v1/commands/?cmd=%s", strings.TrimRight(c.baseURL, "/"), url.QueryEscape(command))
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, reqURL, http.NoBody)
-	if err != nil {
-		return err
-	}
-	resp, err := c.http.Do(req)
-	if err != nil {
-		return err
-	}
-	defer resp.Body.Close()
-	// Volumio may respond 200 or 204 for commands; treat 2xx as success.
-	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		return fmt.Errorf("command %q failed: status %d", command, resp.StatusCode)
-	}
-	return nil
-}
-
-func (c *volumioClient) Play(ctx context.Context) error   { return c.cmd(ctx, "play") }
-func (c *volumioClient) Pause(ctx context.Context) error  { return c.cmd(ctx, "pause") }
-func (c *volumioClient) Stop(ctx context.Context) error   { return c.cmd(ctx, "stop") }
-func (c *volumioClient) Toggle(ctx context.Context) error { return c.cmd(ctx, "toggle") }
-
-// SetVolume sets the absolute volume (0..100).
-func (c *volumioClient) SetVolume(ctx context.Context, vol int) error {
-	if vol < 0 {
-		vol = 0
-	}
-	if vol > 100 {
-		vol = 100
-	}
-	// Build the query properly so &volume is not escaped into the cmd value.
-	reqURL := fmt.Sprintf("%s/api/v1/commands/?cmd=volume&volume=%d", strings.TrimRight(c.baseURL, "/"), vol)
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, reqURL, http.NoBody)
-	if err != nil {
-		return err
-	}
-	resp, err := c.http.Do(req)
-	if err != nil {
-		return err
-	}
-	defer resp.Body.Close()
-	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		return fmt.Errorf("set volume failed: status %d", resp.StatusCode)
-	}
-	return nil
-}
-
-type state struct {
-	Status       string  `json:"status"` // "play","pause","stop"
-	Title        string  `json:"title"`
-	Artist       string  `json:"artist"`
-	Album        string  `json:"album"`
-	Seek         int64   `json:"seek"`
-	Duration     float64 `json:"duration"`
-	Volume       int     `json:"volume"`
-	Repeat       bool    `json:"repeat"`
-	Random       bool    `json:"random"`
-	Consume      bool    `json:"consume"`
-	VolumioVer   string  `json:"volumio_version"`
-	Service      string  `json:"service"`
-	TrackType    string  `json:"trackType"`
-	Samplerate   string  `json:"samplerate"`
-	Bitdepth     string  `json:"bitdepth"`
-	Channels     int     `json:"channels"`
-	Updated      string  `json:"updated"`
-	DisableState bool    `json:"disableUiControls"`
-}
-
-func (c *volumioClient) GetState(ctx context.Context) (state, error) {
-	var s state
-	reqURL := strings.TrimRight(c.baseURL, "/") + "/api/v1/getState"
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, reqURL, http.NoBody)
-	if err != nil {
-		return s, err
-	}
-	resp, err := c.http.Do(req)
-	if err != nil {
-		return s, err
-	}
-	defer resp.Body.Close()
-	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		return s, fmt.Errorf("getState failed: status %d", resp.StatusCode)
-	}
-	dec := json.NewDecoder(resp.Body)
-	if err := dec.Decode(&s); err != nil {
-		return s, err
-	}
-	return s, nil
-}
-
 // TUI
 
 type keymap struct {
@@ -167,6 +51,11 @@ type keymap struct {
 	VolUp     key.Binding
 	VolDown   key.Binding
 	Image     key.Binding
+	Devices   key.Binding
+	Up        key.Binding
+	Down      key.Binding
+	Select    key.Binding
+	Say       key.Binding
 }
 
 func defaultKeymap() keymap {
@@ -185,14 +74,19 @@ func defaultKeymap() keymap {
 		VolUp:     key.NewBinding(key.WithKeys("up"), key.WithHelp("↑", "volume up")),
 		VolDown:   key.NewBinding(key.WithKeys("down"), key.WithHelp("↓", "volume down")),
 		Image:     key.NewBinding(key.WithKeys("i"), key.WithHelp("i", "toggle image")),
+		Devices:   key.NewBinding(key.WithKeys("d"), key.WithHelp("d", "devices")),
+		Up:        key.NewBinding(key.WithKeys("up"), key.WithHelp("↑", "move up")),
+		Down:      key.NewBinding(key.WithKeys("down"), key.WithHelp("↓", "move down")),
+		Select:    key.NewBinding(key.WithKeys("enter"), key.WithHelp("enter", "select device")),
+		Say:       key.NewBinding(key.WithKeys("t"), key.WithHelp("t", "say")),
 	}
 }
 
 type model struct {
-	client     *volumioClient
+	client     *client.VolumioClient
 	hostInput  textinput.Model
 	host       string
-	st         state
+	st         client.State
 	err        error
 	loading    bool
 	pollTicker *time.Ticker
@@ -208,21 +102,71 @@ type model struct {
 	imgColsCached  int
 	imageSeqCached string
 	imageBytesB64  string
+
+	// Device discovery/picker
+	devices       *deviceRegistry
+	deviceCursor  int
+	showDevices   bool
+	deviceFoundCh chan client.DiscoveredHost
+
+	// Push-state subscription. program is set by main() right after the
+	// tea.Program is constructed, so subscription goroutines can deliver
+	// messages with program.Send instead of going through a tea.Cmd.
+	program      *tea.Program
+	socketCancel context.CancelFunc
+
+	// Text-to-speech "say" command.
+	ttsProvider  TTSProvider
+	sayConfig    sayConfig
+	sayInput     textinput.Model
+	saying       bool
+	announcing   bool
+	announceStop chan struct{}
+
+	// Queue/library browse pane.
+	browseKeys   browseKeys
+	showBrowse   bool
+	browseTab    int
+	queueList    list.Model
+	libraryList  list.Model
+	browseURI    string
+	browseStack  []string
+	libraryStats client.CollectionStats
+
+	// MPRIS2 desktop bridge, enabled with --mpris. Left nil (and silently
+	// unused) if the session bus isn't reachable, e.g. a headless box.
+	mprisEnabled bool
+	mprisPlayer  *mpris.Player
+	mprisStateCh chan client.State
+	mprisCancel  context.CancelFunc
 }
 
-func initialModel(host string) *model {
+func initialModel(host string, ttsProvider TTSProvider, cfg sayConfig, mprisEnabled bool) *model {
 	ti := textinput.New()
 	ti.Prompt = "Host: "
 	ti.SetValue(host)
 	ti.CharLimit = 256
 	ti.Blur()
 
+	sayInput := textinput.New()
+	sayInput.Prompt = "Say: "
+	sayInput.CharLimit = 512
+	sayInput.Blur()
+
 	m := &model{
-		hostInput: ti,
-		host:      ti.Value(),
-		keys:      defaultKeymap(),
-		help:      help.New(),
-		showImage: true,
+		hostInput:    ti,
+		host:         ti.Value(),
+		keys:         defaultKeymap(),
+		help:         help.New(),
+		showImage:    true,
+		devices:      newDeviceRegistry(),
+		ttsProvider:  ttsProvider,
+		sayConfig:    cfg,
+		sayInput:     sayInput,
+		browseKeys:   defaultBrowseKeys(),
+		queueList:    newBrowsePaneList("Queue"),
+		libraryList:  newBrowsePaneList("Library"),
+		mprisEnabled: mprisEnabled,
 	}
 
 	if len(volumioPNG) > 0 {
@@ -268,6 +212,14 @@ func ansiCursorPos(row, col int) string {
 	return "\x1b[" + strconv.Itoa(row) + ";" + strconv.Itoa(col) + "H"
 }
 
+// envOr returns the named environment variable's value, or fallback if unset.
+func envOr(name, fallback string) string {
+	if v := strings.TrimSpace(os.Getenv(name)); v != "" {
+		return v
+	}
+	return fallback
+}
+
 func getDefaultHost(ctx context.Context) (string, error) {
 	if v := strings.TrimSpace(os.Getenv("VOLUMIO_URL")); v != "" {
 		return v, nil
@@ -282,124 +234,104 @@ func getDefaultHost(ctx context.Context) (string, error) {
 		}
 		return v, nil
 	}
-	return discoverVolumio(ctx)
-}
 
-// discoverVolumio performs mDNS/Bonjour discovery of Volumio services (_volumio._tcp)
-// and returns the first discovered HTTP base URL (e.g., http://192.168.1.10:3000).
-func discoverVolumio(ctx context.Context) (string, error) {
-	resolver, err := zeroconf.NewResolver(nil)
-	if err != nil {
-		return "", err
+	hosts, err := client.Discover(ctx, 5*time.Second)
+	if err != nil || len(hosts) == 0 {
+		return "", nil
 	}
+	return hosts[0].BaseURL(), nil
+}
 
-	entries := make(chan *zeroconf.ServiceEntry)
-	defer close(entries)
-
-	foundCh := make(chan string, 1)
-	defer close(foundCh)
-
-	// Collect the first viable entry and stop.
-	go func() {
-		for e := range entries {
-			// Prefer IPv4 address if available; otherwise, use hostname.
-			var host string
-			switch {
-			case len(e.AddrIPv4) > 0:
-				host = e.AddrIPv4[0].String()
-			case len(e.AddrIPv6) > 0:
-				// IPv6 literal needs brackets in URLs.
-				host = "[" + e.AddrIPv6[0].String() + "]"
-			case e.HostName != "":
-				// Fallback to hostname; often ends with .local.
-				host = strings.TrimSuffix(e.HostName, ".") // normalize trailing dot
-			default:
-			}
+// deviceRegistry tracks every Volumio device discovered so far, in the order
+// first seen, so the device-picker pane has something stable to render.
+type deviceRegistry struct {
+	order   []string
+	devices map[string]client.DiscoveredHost
+}
 
-			if host == "" || e.Port == 0 {
-				continue
-			}
-			// Construct Volumio base URL. Volumio UI usually runs on port 3000.
-			addr := "http://" + net.JoinHostPort(host, strconv.Itoa(int(rune(e.Port))))
-			select {
-			case foundCh <- addr:
-			default:
-			}
-			return
-		}
-	}()
+func newDeviceRegistry() *deviceRegistry {
+	return &deviceRegistry{devices: make(map[string]client.DiscoveredHost)}
+}
 
-	if err := resolver.Browse(ctx, "_Volumio._tcp", "local.", entries); err != nil {
-		return "", err
+func (r *deviceRegistry) add(d client.DiscoveredHost) {
+	if _, ok := r.devices[d.Address]; !ok {
+		r.order = append(r.order, d.Address)
 	}
+	r.devices[d.Address] = d
+}
 
-	select {
-	case <-ctx.Done():
-		return "", ctx.Err()
-	case addr := <-foundCh:
-		return addr, nil
-	case <-time.After(5 * time.Second):
-		return "", nil
+func (r *deviceRegistry) list() []client.DiscoveredHost {
+	out := make([]client.DiscoveredHost, 0, len(r.order))
+	for _, addr := range r.order {
+		out = append(out, r.devices[addr])
 	}
+	return out
 }
 
 type (
-	refreshMsg   state
-	errorMsg     error
-	connectedMsg struct{ ok bool }
+	refreshMsg         client.State
+	errorMsg           error
+	connectedMsg       struct{ ok bool }
+	deviceFoundMsg     client.DiscoveredHost
+	socketConnectedMsg struct{}
+	queueMsg           []client.QueueItem
 )
 
 func (m *model) Init() tea.Cmd {
 	return tea.Batch(
 		m.connectCmd(m.host),
 		m.startPolling(),
+		m.startDeviceDiscovery(),
 	)
 }
 
 func (m *model) connectCmd(host string) tea.Cmd {
 	return func() tea.Msg {
-		client, err := newVolumioClient(host)
+		c, err := client.NewVolumioClient(host)
 		if err != nil {
 			return errorMsg(err)
 		}
-		// Quick connectivity probe (resolve host) to provide immediate feedback.
-		if err := probeHost(client.baseURL); err != nil {
+		m.client = c // set even on probe failure, so the user can retry
+		// Quick connectivity probe to provide immediate feedback.
+		if err := c.ProbeHost(); err != nil {
 			m.connected = false
-			m.client = client // still set, user can retry
 			return errorMsg(fmt.Errorf("connect: %w", err))
 		}
-		m.client = client
 		m.connected = true
+		m.startMPRIS()
 		return connectedMsg{ok: true}
 	}
 }
 
-func probeHost(raw string) error {
-	u, err := url.Parse(raw)
-	if err != nil {
-		return err
-	}
-	host := u.Host
-	if !strings.Contains(host, ":") {
-		host += ":80"
+// startMPRIS connects the MPRIS2 bridge to m.client if --mpris was passed and
+// it isn't already running. Failures (e.g. no session D-Bus, common when
+// running headless or over SSH) are logged to stderr and otherwise ignored:
+// the TUI works fine without it.
+func (m *model) startMPRIS() {
+	if !m.mprisEnabled || m.mprisPlayer != nil || m.client == nil {
+		return
 	}
-	d := net.Dialer{Timeout: 2 * time.Second}
-	ctx1, cancel1 := context.WithTimeout(context.Background(), 2*time.Second)
-	defer cancel1()
-	conn, err := d.DialContext(ctx1, "tcp", host)
+	player, err := mpris.New(m.client)
 	if err != nil {
-		// Try common Volumio port if user omitted it
-		host3000 := u.Hostname() + ":3000"
-		ctx2, cancel2 := context.WithTimeout(context.Background(), 2*time.Second)
-		defer cancel2()
-		if c2, err2 := d.DialContext(ctx2, "tcp", host3000); err2 == nil {
-			_ = c2.Close()
-			return nil
-		}
-		return err
+		fmt.Fprintln(os.Stderr, "mpris: disabled:", err)
+		return
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	m.mprisPlayer = player
+	m.mprisCancel = cancel
+	m.mprisStateCh = make(chan client.State)
+	go player.Run(ctx, m.mprisStateCh)
+}
+
+func (m *model) stopMPRIS() {
+	if m.mprisCancel != nil {
+		m.mprisCancel()
+		m.mprisCancel = nil
+	}
+	if m.mprisPlayer != nil {
+		_ = m.mprisPlayer.Close()
+		m.mprisPlayer = nil
 	}
-	_ = conn.Close()
-	return nil
 }
 
 func (m *model) startPolling() tea.Cmd {
@@ -409,6 +341,110 @@ func (m *model) startPolling() tea.Cmd {
 	}
 }
 
+// startDeviceDiscovery launches continuous mDNS browsing for the lifetime of
+// the program and returns the command that waits for the first result.
+func (m *model) startDeviceDiscovery() tea.Cmd {
+	m.deviceFoundCh = make(chan client.DiscoveredHost)
+	go func() { _ = client.DiscoverContinuous(context.Background(), m.deviceFoundCh) }()
+	return m.waitForDeviceCmd()
+}
+
+// startSocketSubscription opens a Socket.IO push-state subscription and stops
+// the poll ticker once the first state arrives on it: the stream reports
+// changes immediately instead of on a fixed interval. If the handshake fails,
+// Subscribe polls GetState itself and its first polled state stops the
+// ticker the same way, so updates keep arriving on the poll interval.
+func (m *model) startSocketSubscription() tea.Cmd {
+	return func() tea.Msg {
+		if m.client == nil {
+			return nil
+		}
+		ctx, cancel := context.WithCancel(context.Background())
+		m.socketCancel = cancel
+
+		stateCh, queueCh, err := m.client.Subscribe(ctx)
+		if err != nil {
+			cancel()
+			return nil
+		}
+
+		go func() {
+			connected := false
+			for s := range stateCh {
+				if !connected {
+					m.program.Send(socketConnectedMsg{})
+					connected = true
+				}
+				m.program.Send(refreshMsg(s))
+			}
+		}()
+		go func() {
+			for q := range queueCh {
+				m.program.Send(queueMsg(q))
+			}
+		}()
+		return nil
+	}
+}
+
+// reconnectHost switches to the new m.host: it cancels any live socket
+// subscription, restarts the poll ticker as a safety net for the reconnect
+// window, and kicks off a fresh connect and subscription attempt.
+func (m *model) reconnectHost() tea.Cmd {
+	if m.socketCancel != nil {
+		m.socketCancel()
+		m.socketCancel = nil
+	}
+	if m.pollTicker == nil {
+		m.pollTicker = time.NewTicker(pollInterval)
+	}
+	return tea.Batch(
+		m.connectCmd(m.host),
+		m.refreshCmd(),
+	)
+}
+
+// startSayCmd runs the duck/pause/synthesize/play/wait/restore state machine
+// for text, blocking the whole time (bubbletea runs each tea.Cmd on its own
+// goroutine, so this doesn't stall the UI). It refreshes state once done.
+func (m *model) startSayCmd(text string) tea.Cmd {
+	return func() tea.Msg {
+		if m.client == nil {
+			return errorMsg(errors.New("not connected"))
+		}
+		m.announcing = true
+		m.announceStop = make(chan struct{})
+		err := runSay(context.Background(), m.client, m.ttsProvider, text, defaultSayLang, m.sayConfig, m.announceStop)
+		m.announcing = false
+		m.announceStop = nil
+		if err != nil {
+			return errorMsg(err)
+		}
+		return m.refreshCmd()()
+	}
+}
+
+// stopAnnouncing signals a running say state machine to skip its wait and
+// skip resuming playback, as if the user had asked for silence right now.
+func (m *model) stopAnnouncing() {
+	if m.announceStop != nil {
+		close(m.announceStop)
+		m.announceStop = nil
+	}
+}
+
+// waitForDeviceCmd blocks for the next discovered device, then re-arms
+// itself from Update so discovery keeps streaming into the device pane.
+func (m *model) waitForDeviceCmd() tea.Cmd {
+	return func() tea.Msg {
+		d, ok := <-m.deviceFoundCh
+		if !ok {
+			return nil
+		}
+		return deviceFoundMsg(d)
+	}
+}
+
 func (m *model) refreshCmd() tea.Cmd {
 	if m.client == nil {
 		return nil
@@ -487,10 +523,7 @@ func (m *model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				m.host = val
 				m.editing = false
 				m.hostInput.Blur()
-				return m, tea.Batch(
-					m.connectCmd(m.host),
-					m.refreshCmd(),
-				)
+				return m, m.reconnectHost()
 			case key.Matches(msg, m.keys.Cancel):
 				m.editing = false
 				m.hostInput.Blur()
@@ -502,11 +535,105 @@ func (m *model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			}
 		}
 
+		if m.saying {
+			switch {
+			case key.Matches(msg, m.keys.SaveHost): // enter: shared "confirm" binding
+				text := strings.TrimSpace(m.sayInput.Value())
+				m.saying = false
+				m.sayInput.Blur()
+				m.sayInput.SetValue("")
+				if text == "" {
+					return m, nil
+				}
+				return m, m.startSayCmd(text)
+			case key.Matches(msg, m.keys.Cancel):
+				m.saying = false
+				m.sayInput.Blur()
+				m.sayInput.SetValue("")
+				return m, nil
+			default:
+				var cmd tea.Cmd
+				m.sayInput, cmd = m.sayInput.Update(msg)
+				return m, cmd
+			}
+		}
+
+		if m.showBrowse {
+			switch {
+			case key.Matches(msg, m.browseKeys.Browse), key.Matches(msg, m.keys.Cancel):
+				m.showBrowse = false
+				return m, nil
+			case key.Matches(msg, m.browseKeys.Tab):
+				m.browseTab = 1 - m.browseTab
+				return m, nil
+			case key.Matches(msg, m.browseKeys.Back):
+				if m.browseTab == browseTabLibrary && len(m.browseStack) > 0 {
+					m.browseStack = m.browseStack[:len(m.browseStack)-1]
+					uri := ""
+					if len(m.browseStack) > 0 {
+						uri = m.browseStack[len(m.browseStack)-1]
+					}
+					return m, m.browseCmd(uri)
+				}
+				return m, nil
+			case key.Matches(msg, m.keys.SaveHost): // enter: play/navigate selected item
+				return m, m.browseSelect()
+			case key.Matches(msg, m.browseKeys.Add):
+				return m, m.browseAddSelected()
+			case key.Matches(msg, m.browseKeys.Replace):
+				return m, m.browseSelect()
+			case key.Matches(msg, m.browseKeys.Remove):
+				return m, m.browseRemoveSelected()
+			case key.Matches(msg, m.browseKeys.Clear):
+				return m, m.clearQueueCmd()
+			default:
+				var cmd tea.Cmd
+				if m.browseTab == browseTabQueue {
+					m.queueList, cmd = m.queueList.Update(msg)
+				} else {
+					m.libraryList, cmd = m.libraryList.Update(msg)
+				}
+				return m, cmd
+			}
+		}
+
+		if m.showDevices {
+			switch {
+			case key.Matches(msg, m.keys.Up):
+				if m.deviceCursor > 0 {
+					m.deviceCursor--
+				}
+				return m, nil
+			case key.Matches(msg, m.keys.Down):
+				if m.deviceCursor < len(m.devices.list())-1 {
+					m.deviceCursor++
+				}
+				return m, nil
+			case key.Matches(msg, m.keys.Select):
+				devices := m.devices.list()
+				if m.deviceCursor >= len(devices) {
+					return m, nil
+				}
+				m.showDevices = false
+				m.host = devices[m.deviceCursor].BaseURL()
+				m.loading = true
+				return m, m.reconnectHost()
+			case key.Matches(msg, m.keys.Devices), key.Matches(msg, m.keys.Cancel):
+				m.showDevices = false
+				return m, nil
+			}
+			return m, nil
+		}
+
 		switch {
 		case key.Matches(msg, m.keys.Quit):
 			if m.pollTicker != nil {
 				m.pollTicker.Stop()
 			}
+			if m.socketCancel != nil {
+				m.socketCancel()
+			}
+			m.stopMPRIS()
 			return m, tea.Quit
 		case key.Matches(msg, m.keys.Help):
 			m.showHelp = !m.showHelp
@@ -518,6 +645,13 @@ func (m *model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		case key.Matches(msg, m.keys.Image):
 			m.showImage = !m.showImage
 			return m, nil
+		case key.Matches(msg, m.keys.Devices):
+			m.showDevices = !m.showDevices
+			m.deviceCursor = 0
+			return m, nil
+		case key.Matches(msg, m.browseKeys.Browse):
+			m.showBrowse = true
+			return m, tea.Batch(m.refreshQueueCmd(), m.browseCmd(m.browseURI))
 		case key.Matches(msg, m.keys.PlayPause):
 			m.loading = true
 			cmd := m.toggleCmd()
@@ -532,8 +666,15 @@ func (m *model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m, cmd
 		case key.Matches(msg, m.keys.Stop):
 			m.loading = true
+			if m.announcing {
+				m.stopAnnouncing()
+			}
 			cmd := m.stopCmd()
 			return m, cmd
+		case key.Matches(msg, m.keys.Say):
+			m.saying = true
+			m.sayInput.Focus()
+			return m, nil
 		case key.Matches(msg, m.keys.Refresh):
 			m.loading = true
 			cmd := m.refreshCmd()
@@ -566,25 +707,57 @@ func (m *model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case tea.WindowSizeMsg:
 		m.winW = msg.Width
 		m.winH = msg.Height
+		listW := msg.Width - 4
+		if msg.Width >= wideBrowseWidth {
+			listW = msg.Width/2 - 4
+		}
+		listH := msg.Height / 2
+		if listH < 5 {
+			listH = 5
+		}
+		m.queueList.SetSize(listW, listH)
+		m.libraryList.SetSize(listW, listH)
 		return m, nil
 
 	case refreshMsg:
-		m.st = state(msg)
+		m.st = client.State(msg)
 		m.err = nil
 		m.loading = false
+		if m.mprisStateCh != nil {
+			select {
+			case m.mprisStateCh <- m.st:
+			default:
+			}
+		}
 		return m, nil
 
 	case connectedMsg:
-		// After a successful connection, perform an initial refresh.
+		// After a successful connection, perform an initial refresh and open
+		// the push-state subscription. Both need m.client set, which only
+		// just happened in connectCmd, so this can't be batched alongside it.
 		m.loading = true
-		cmd := m.refreshCmd()
-		return m, cmd
+		return m, tea.Batch(m.refreshCmd(), m.startSocketSubscription())
 
 	case errorMsg:
 		m.err = msg
 		m.loading = false
 		return m, nil
 
+	case deviceFoundMsg:
+		m.devices.add(client.DiscoveredHost(msg))
+		return m, m.waitForDeviceCmd()
+
+	case socketConnectedMsg:
+		if m.pollTicker != nil {
+			m.pollTicker.Stop()
+			m.pollTicker = nil
+		}
+		return m, nil
+
+	case queueMsg:
+		m.setQueue(msg)
+		return m, nil
+
 	case tea.Msg:
 		// fallthrough
 	}
@@ -672,6 +845,15 @@ func (m *model) View() string {
 		b.WriteString(dimStyle.Render("Press Enter to save, Esc to cancel\n"))
 	}
 
+	// Say
+	if m.saying {
+		b.WriteString("\n" + m.sayInput.View() + "\n")
+		b.WriteString(dimStyle.Render("Press Enter to say, Esc to cancel\n"))
+	}
+	if m.announcing {
+		b.WriteString(dimStyle.Render("\nSpeaking... (stop to cancel)\n"))
+	}
+
 	// Playback info
 	statusText := strings.ToLower(m.st.Status)
 	switch statusText {
@@ -700,6 +882,16 @@ func (m *model) View() string {
 	b.WriteString(fmt.Sprintf("%s %s\n", labelStyle.Render("Track:   "), valueStyle.Render(track)))
 	b.WriteString(fmt.Sprintf("%s %s%%\n", labelStyle.Render("Volume:  "), valueStyle.Render(strconv.Itoa(m.st.Volume))))
 
+	// Device picker
+	if m.showDevices {
+		b.WriteString(m.renderDevices())
+	}
+
+	// Queue/library browse pane
+	if m.showBrowse {
+		b.WriteString(m.renderBrowse())
+	}
+
 	// Error
 	if m.err != nil {
 		b.WriteString("\n" + errorStyle.Render("Error: "+m.err.Error()) + "\n")
@@ -715,20 +907,53 @@ func (m *model) View() string {
 	if m.showHelp {
 		b.WriteString(m.help.FullHelpView([][]key.Binding{
 			{m.keys.PlayPause, m.keys.Play, m.keys.Pause, m.keys.Stop, m.keys.Refresh},
-			{m.keys.VolUp, m.keys.VolDown, m.keys.Image},
+			{m.keys.VolUp, m.keys.VolDown, m.keys.Image, m.keys.Devices, m.keys.Say, m.browseKeys.Browse},
 			{m.keys.EditHost, m.keys.SaveHost, m.keys.Cancel, m.keys.Help, m.keys.Quit},
 		}))
 	} else {
 		b.WriteString(m.help.ShortHelpView([]key.Binding{
-			m.keys.PlayPause, m.keys.Stop, m.keys.VolUp, m.keys.VolDown, m.keys.Image, m.keys.EditHost, m.keys.Refresh, m.keys.Help, m.keys.Quit,
+			m.keys.PlayPause, m.keys.Stop, m.keys.VolUp, m.keys.VolDown, m.keys.Image, m.keys.Devices, m.keys.Say, m.browseKeys.Browse, m.keys.EditHost, m.keys.Refresh, m.keys.Help, m.keys.Quit,
 		}))
 	}
 
 	return b.String()
 }
 
+// renderDevices renders the device-picker pane: every Volumio host
+// discovered via mDNS so far, with the current selection highlighted.
+func (m *model) renderDevices() string {
+	var b strings.Builder
+	b.WriteString("\n" + titleStyle.Render("Devices") + "\n")
+
+	devices := m.devices.list()
+	if len(devices) == 0 {
+		b.WriteString(dimStyle.Render("Searching for Volumio devices on the LAN...\n"))
+		return b.String()
+	}
+
+	for i, d := range devices {
+		line := fmt.Sprintf("%s (%s)", d.Name, d.BaseURL())
+		if d.Version != "" {
+			line += " v" + d.Version
+		}
+		if i == m.deviceCursor {
+			line = "> " + valueStyle.Render(line)
+		} else {
+			line = "  " + dimStyle.Render(line)
+		}
+		b.WriteString(line + "\n")
+	}
+	b.WriteString(dimStyle.Render("↑/↓ select, enter connect, esc/d close\n"))
+	return b.String()
+}
+
 func main() {
 	versionFlag := flag.Bool("v", false, "Print version")
+	ttsProviderFlag := flag.String("tts-provider", envOr("VOLUMIO_TTS_PROVIDER", "google"),
+		`TTS provider for the say command ("google" or "url")`)
+	duckVolumeFlag := flag.Int("tts-duck-volume", defaultDuckVolume, "volume (0-100) to duck to while speaking")
+	autoResumeFlag := flag.Bool("tts-auto-resume", true, "resume playback after speaking if it was playing")
+	mprisFlag := flag.Bool("mpris", false, "expose the player as an MPRIS2 D-Bus service for desktop media keys")
 	flag.Parse()
 
 	if *versionFlag {
@@ -736,6 +961,15 @@ func main() {
 		os.Exit(0)
 	}
 
+	// VOLUMIO_TTS_API_KEY is read here so future keyed providers (e.g. a
+	// cloud TTS API) have a single place to plumb a secret from; neither
+	// built-in provider needs one.
+	ttsProvider, err := newTTSProvider(*ttsProviderFlag, os.Getenv("VOLUMIO_TTS_API_KEY"))
+	if err != nil {
+		fmt.Println("Error:", err)
+		os.Exit(1)
+	}
+
 	host, err := getDefaultHost(context.Background())
 	if err != nil {
 		fmt.Println("Error:", err)
@@ -745,7 +979,9 @@ func main() {
 		fmt.Println("No default host found")
 		os.Exit(1)
 	}
-	p := tea.NewProgram(initialModel(host), tea.WithAltScreen())
+	m := initialModel(host, ttsProvider, sayConfig{DuckVolume: *duckVolumeFlag, AutoResume: *autoResumeFlag}, *mprisFlag)
+	p := tea.NewProgram(m, tea.WithAltScreen())
+	m.program = p
 	if _, err := p.Run(); err != nil {
 		fmt.Println("Error:", err)
 		os.Exit(1)
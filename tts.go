@@ -0,0 +1,194 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/bakito/volumio-tui/internal/client"
+)
+
+const (
+	defaultDuckVolume  = 20
+	defaultSayLang     = "en"
+	minAnnounceSeconds = 2.0
+	wordsPerMinute     = 150
+
+	// announcePollInterval is how often waitForAnnouncement polls GetState
+	// when the TTS provider can't report a duration up front. A package var
+	// (rather than a const) so tests can shrink it instead of sleeping real
+	// time.
+	announcePollIntervalDefault = 500 * time.Millisecond
+)
+
+var announcePollInterval = announcePollIntervalDefault
+
+// TTSProvider synthesizes text into a URL Volumio can stream. seconds is the
+// estimated (or exact) playback duration, or 0 if unknown, in which case the
+// caller polls state until playback ends instead of waiting a fixed amount
+// of time.
+type TTSProvider interface {
+	Synthesize(ctx context.Context, text, lang string) (audioURL string, seconds float64, err error)
+}
+
+// newTTSProvider resolves the --tts-provider flag/env value to a provider.
+// apiKey is threaded through for providers that need one; neither of the
+// built-in providers does.
+func newTTSProvider(name, apiKey string) (TTSProvider, error) {
+	switch name {
+	case "", "google":
+		return googleTranslateTTSProvider{}, nil
+	case "url":
+		return externalURLTTSProvider{}, nil
+	default:
+		return nil, fmt.Errorf("unknown tts provider %q (want %q or %q)", name, "google", "url")
+	}
+}
+
+// googleTranslateTTSProvider synthesizes speech via Google Translate's
+// keyless, undocumented TTS endpoint. It doesn't report a duration, so one is
+// estimated from the text length.
+type googleTranslateTTSProvider struct{}
+
+func (googleTranslateTTSProvider) Synthesize(_ context.Context, text, lang string) (string, float64, error) {
+	if strings.TrimSpace(text) == "" {
+		return "", 0, fmt.Errorf("say: empty text")
+	}
+	if lang == "" {
+		lang = defaultSayLang
+	}
+	audioURL := "https://translate.google.com/translate_tts?ie=UTF-8&client=tw-ob&tl=" +
+		url.QueryEscape(lang) + "&q=" + url.QueryEscape(text)
+	return audioURL, estimateSpeechSeconds(text), nil
+}
+
+// estimateSpeechSeconds roughly estimates spoken duration at wordsPerMinute,
+// since the Google Translate endpoint doesn't report one.
+func estimateSpeechSeconds(text string) float64 {
+	words := len(strings.Fields(text))
+	seconds := float64(words) / (wordsPerMinute / 60.0)
+	if seconds < minAnnounceSeconds {
+		seconds = minAnnounceSeconds
+	}
+	return seconds
+}
+
+// externalURLTTSProvider treats the entered text as an already-hosted MP3
+// URL, for setups that generate announcements out-of-band (e.g. a
+// home-automation pipeline that drops files on a web server). Duration is
+// unknown, so runSay polls state until playback ends.
+type externalURLTTSProvider struct{}
+
+func (externalURLTTSProvider) Synthesize(_ context.Context, text, _ string) (string, float64, error) {
+	if !strings.Contains(text, "://") {
+		return "", 0, fmt.Errorf("say: external URL provider expects a URL, got %q", text)
+	}
+	return text, 0, nil
+}
+
+// sayConfig holds the user-configurable parts of the say flow.
+type sayConfig struct {
+	DuckVolume int
+	AutoResume bool
+}
+
+// sayPlayer is the narrow subset of client.VolumioClient that runSay needs,
+// so the state machine can be driven by a fake in tests without real
+// HTTP/socket calls.
+type sayPlayer interface {
+	GetState(ctx context.Context) (client.State, error)
+	SetVolume(ctx context.Context, vol int) error
+	Pause(ctx context.Context) error
+	Play(ctx context.Context) error
+	PlayURL(ctx context.Context, rawURL string) error
+}
+
+// runSay drives the duck/pause/synthesize/play/wait/restore state machine:
+// it snapshots p's state, ducks the volume and pauses if playing, synthesizes
+// text via provider, plays the result, waits for it to finish (or for stop to
+// fire, e.g. because the user pressed stop mid-announcement), then restores
+// the previous volume and resumes playback if it was playing and stop didn't
+// fire. If synthesizing or playing the announcement fails, it restores the
+// volume without resuming playback and returns the error.
+func runSay(ctx context.Context, p sayPlayer, provider TTSProvider, text, lang string, cfg sayConfig, stop <-chan struct{}) error {
+	before, err := p.GetState(ctx)
+	if err != nil {
+		return fmt.Errorf("say: snapshot state: %w", err)
+	}
+	wasPlaying := before.Status == "play"
+
+	if err := p.SetVolume(ctx, cfg.DuckVolume); err != nil {
+		return fmt.Errorf("say: duck volume: %w", err)
+	}
+	if wasPlaying {
+		if err := p.Pause(ctx); err != nil {
+			return fmt.Errorf("say: pause: %w", err)
+		}
+	}
+
+	audioURL, seconds, err := provider.Synthesize(ctx, text, lang)
+	if err != nil {
+		_ = restoreAfterSay(ctx, p, before, cfg, true)
+		return fmt.Errorf("say: synthesize: %w", err)
+	}
+
+	if err := p.PlayURL(ctx, audioURL); err != nil {
+		_ = restoreAfterSay(ctx, p, before, cfg, true)
+		return fmt.Errorf("say: play announcement: %w", err)
+	}
+
+	interrupted := waitForAnnouncement(ctx, p, seconds, stop)
+	return restoreAfterSay(ctx, p, before, cfg, interrupted)
+}
+
+// waitForAnnouncement blocks until the announcement should be done: for
+// seconds > 0 it simply waits that long; otherwise (a provider that can't
+// report a duration) it polls GetState until playback leaves "play". It
+// returns early, reporting interrupted, if stop fires or ctx is cancelled --
+// e.g. the user pressed stop mid-announcement.
+func waitForAnnouncement(ctx context.Context, p sayPlayer, seconds float64, stop <-chan struct{}) bool {
+	if seconds > 0 {
+		select {
+		case <-time.After(time.Duration(seconds * float64(time.Second))):
+			return false
+		case <-stop:
+			return true
+		case <-ctx.Done():
+			return true
+		}
+	}
+
+	ticker := time.NewTicker(announcePollInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stop:
+			return true
+		case <-ctx.Done():
+			return true
+		case <-ticker.C:
+			s, err := p.GetState(ctx)
+			if err == nil && s.Status != "play" {
+				return false
+			}
+		}
+	}
+}
+
+// restoreAfterSay restores the volume p had before the announcement started,
+// and resumes playback if it was playing and interrupted is false. runSay
+// sets interrupted when the user pressed stop mid-announcement and when the
+// announcement failed to synthesize or play.
+func restoreAfterSay(ctx context.Context, p sayPlayer, before client.State, cfg sayConfig, interrupted bool) error {
+	if err := p.SetVolume(ctx, before.Volume); err != nil {
+		return fmt.Errorf("say: restore volume: %w", err)
+	}
+	if cfg.AutoResume && before.Status == "play" && !interrupted {
+		if err := p.Play(ctx); err != nil {
+			return fmt.Errorf("say: resume playback: %w", err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,180 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/bakito/volumio-tui/internal/client"
+)
+
+// fakeSayPlayer is an in-memory sayPlayer for exercising runSay's
+// duck/pause/restore state machine without a real Volumio instance.
+type fakeSayPlayer struct {
+	st client.State
+
+	playURLErr error
+
+	getStateCalls int
+	// playingUntilCall, if > 0, makes GetState report Status "play" until
+	// the call count reaches this value, then "stop" -- simulating an
+	// external-URL announcement that finishes on its own.
+	playingUntilCall int
+
+	paused  bool
+	played  bool
+	playURL string
+}
+
+func (p *fakeSayPlayer) GetState(context.Context) (client.State, error) {
+	p.getStateCalls++
+	if p.playingUntilCall > 0 {
+		s := p.st
+		if p.getStateCalls >= p.playingUntilCall {
+			s.Status = "stop"
+		} else {
+			s.Status = "play"
+		}
+		return s, nil
+	}
+	return p.st, nil
+}
+
+func (p *fakeSayPlayer) SetVolume(_ context.Context, vol int) error {
+	p.st.Volume = vol
+	return nil
+}
+
+func (p *fakeSayPlayer) Pause(context.Context) error {
+	p.paused = true
+	p.st.Status = "pause"
+	return nil
+}
+
+func (p *fakeSayPlayer) Play(context.Context) error {
+	p.played = true
+	p.st.Status = "play"
+	return nil
+}
+
+func (p *fakeSayPlayer) PlayURL(_ context.Context, rawURL string) error {
+	if p.playURLErr != nil {
+		return p.playURLErr
+	}
+	p.playURL = rawURL
+	return nil
+}
+
+// fixedProvider returns a fixed URL/duration pair, for deterministic tests.
+type fixedProvider struct {
+	audioURL string
+	seconds  float64
+}
+
+func (f fixedProvider) Synthesize(context.Context, string, string) (string, float64, error) {
+	return f.audioURL, f.seconds, nil
+}
+
+func TestRunSay_DucksPausesAndRestores(t *testing.T) {
+	p := &fakeSayPlayer{st: client.State{Status: "play", Volume: 80}}
+	cfg := sayConfig{DuckVolume: 10, AutoResume: true}
+	stop := make(chan struct{})
+
+	err := runSay(context.Background(), p, fixedProvider{audioURL: "http://example.com/a.mp3", seconds: 0.01}, "hello", "en", cfg, stop)
+	if err != nil {
+		t.Fatalf("runSay: %v", err)
+	}
+	if !p.paused {
+		t.Error("expected Pause to be called since the player was playing")
+	}
+	if p.playURL != "http://example.com/a.mp3" {
+		t.Errorf("expected PlayURL to be called with the synthesized URL, got %q", p.playURL)
+	}
+	if !p.played {
+		t.Error("expected Play to resume after the announcement since AutoResume is set")
+	}
+	if p.st.Volume != 80 {
+		t.Errorf("expected volume restored to 80, got %d", p.st.Volume)
+	}
+}
+
+// TestRunSay_StopMidAnnouncement covers the user pressing stop while the
+// announcement is still playing: playback must not be resumed even though
+// the player was playing before, but the volume must still be restored.
+func TestRunSay_StopMidAnnouncement(t *testing.T) {
+	p := &fakeSayPlayer{st: client.State{Status: "play", Volume: 80}}
+	cfg := sayConfig{DuckVolume: 10, AutoResume: true}
+	stop := make(chan struct{})
+	close(stop) // already stopped before waitForAnnouncement is even reached
+
+	err := runSay(context.Background(), p, fixedProvider{audioURL: "http://example.com/a.mp3", seconds: 30}, "hello", "en", cfg, stop)
+	if err != nil {
+		t.Fatalf("runSay: %v", err)
+	}
+	if p.played {
+		t.Error("expected Play not to be called: the user pressed stop mid-announcement")
+	}
+	if p.st.Volume != 80 {
+		t.Errorf("expected volume restored to 80, got %d", p.st.Volume)
+	}
+}
+
+// TestRunSay_StreamErrorStillRestores covers the stream URL failing to play
+// (e.g. the Volumio playurl command 404ing): the error must surface, but the
+// player's volume must still be restored rather than left ducked. Playback is
+// not resumed, since the announcement never played.
+func TestRunSay_StreamErrorStillRestores(t *testing.T) {
+	p := &fakeSayPlayer{st: client.State{Status: "play", Volume: 80}, playURLErr: errors.New("status 404")}
+	cfg := sayConfig{DuckVolume: 10, AutoResume: true}
+	stop := make(chan struct{})
+
+	err := runSay(context.Background(), p, fixedProvider{audioURL: "http://example.com/missing.mp3", seconds: 5}, "hello", "en", cfg, stop)
+	if err == nil {
+		t.Fatal("expected an error from PlayURL failing")
+	}
+	if p.played {
+		t.Error("expected Play not to be called when the announcement never played")
+	}
+	if p.st.Volume != 80 {
+		t.Errorf("expected volume restored to 80, got %d", p.st.Volume)
+	}
+}
+
+// TestRunSay_PollsUntilPlaybackEnds covers a provider that can't report a
+// duration (seconds == 0, as externalURLTTSProvider does): runSay should
+// poll state until playback leaves "play" rather than waiting a fixed time.
+func TestRunSay_PollsUntilPlaybackEnds(t *testing.T) {
+	old := announcePollInterval
+	announcePollInterval = time.Millisecond
+	defer func() { announcePollInterval = old }()
+
+	p := &fakeSayPlayer{st: client.State{Status: "play", Volume: 80}, playingUntilCall: 3}
+	cfg := sayConfig{DuckVolume: 10, AutoResume: true}
+	stop := make(chan struct{})
+
+	err := runSay(context.Background(), p, fixedProvider{audioURL: "http://example.com/a.mp3", seconds: 0}, "hello", "en", cfg, stop)
+	if err != nil {
+		t.Fatalf("runSay: %v", err)
+	}
+	if !p.played {
+		t.Error("expected Play to resume once polling observed playback had ended")
+	}
+}
+
+func TestRunSay_NotPlayingBeforeDoesNotResume(t *testing.T) {
+	p := &fakeSayPlayer{st: client.State{Status: "stop", Volume: 50}}
+	cfg := sayConfig{DuckVolume: 10, AutoResume: true}
+	stop := make(chan struct{})
+
+	err := runSay(context.Background(), p, fixedProvider{audioURL: "http://example.com/a.mp3", seconds: 0.01}, "hello", "en", cfg, stop)
+	if err != nil {
+		t.Fatalf("runSay: %v", err)
+	}
+	if p.paused {
+		t.Error("expected Pause not to be called since the player wasn't playing")
+	}
+	if p.played {
+		t.Error("expected Play not to be called since the player wasn't playing before")
+	}
+}